@@ -0,0 +1,105 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/cluster-api/pkg/apis/cluster/common"
+)
+
+// MachineError is an error returned by the Actuator that carries the reason
+// to report on the machine status, so callers can tell a permanent
+// misconfiguration from a failure that is worth retrying.
+type MachineError struct {
+	Reason  common.MachineStatusError
+	Message string
+	// Err is the error that caused this one, e.g. the error returned by
+	// the AWS API. It may be nil.
+	Err error
+}
+
+func (e *MachineError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+// Unwrap returns the error that caused e, if any.
+func (e *MachineError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidMachineConfiguration creates a new error when the machine's provider
+// config can't be acted upon. Retrying will not help until the spec is fixed.
+func InvalidMachineConfiguration(msg string, args ...interface{}) *MachineError {
+	return &MachineError{
+		Reason:  common.InvalidConfigurationMachineError,
+		Message: fmt.Sprintf(msg, args...),
+	}
+}
+
+// CreateMachine creates a new error for when creating a machine fails
+// because of err.
+func CreateMachine(err error, msg string, args ...interface{}) *MachineError {
+	return &MachineError{
+		Reason:  common.CreateMachineError,
+		Message: fmt.Sprintf(msg, args...),
+		Err:     err,
+	}
+}
+
+// DeleteMachine creates a new error for when deleting a machine fails
+// because of err.
+func DeleteMachine(err error, msg string, args ...interface{}) *MachineError {
+	return &MachineError{
+		Reason:  common.DeleteMachineError,
+		Message: fmt.Sprintf(msg, args...),
+		Err:     err,
+	}
+}
+
+// IsInvalidMachineConfiguration returns true if err reports an invalid
+// machine configuration, which should not be retried.
+func IsInvalidMachineConfiguration(err error) bool {
+	machineErr, ok := err.(*MachineError)
+	return ok && machineErr.Reason == common.InvalidConfigurationMachineError
+}
+
+// invalidConfigurationEC2ErrorCodes are EC2 API error codes caused by the
+// machine's provider config. Retrying the request will not help.
+var invalidConfigurationEC2ErrorCodes = map[string]bool{
+	"InvalidParameterValue":           true,
+	"InvalidParameterCombination":     true,
+	"InvalidBlockDeviceMapping":       true,
+	"InvalidSubnetID.NotFound":        true,
+	"InvalidGroup.NotFound":           true,
+	"InvalidKeyPair.NotFound":         true,
+	"InsufficientCapacityReservation": true,
+	"Unsupported":                     true,
+}
+
+// classifyEC2ErrorCode returns the reason to report for an EC2 API error
+// code. Codes caused by the provider config map to
+// InvalidConfigurationMachineError. Any other code, e.g.
+// RequestLimitExceeded or InsufficientInstanceCapacity, is worth retrying
+// and maps to reason.
+func classifyEC2ErrorCode(code string, reason common.MachineStatusError) common.MachineStatusError {
+	if invalidConfigurationEC2ErrorCodes[code] || strings.HasPrefix(code, "InvalidAMIID.") {
+		return common.InvalidConfigurationMachineError
+	}
+	return reason
+}
@@ -0,0 +1,94 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"errors"
+	"testing"
+
+	"sigs.k8s.io/cluster-api/pkg/apis/cluster/common"
+)
+
+func TestIsInvalidMachineConfiguration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "invalid configuration",
+			err:      InvalidMachineConfiguration("unknown instance type %q", "m9.huge"),
+			expected: true,
+		},
+		{
+			name:     "create failure",
+			err:      CreateMachine(errors.New("RequestLimitExceeded"), "unable to run instance"),
+			expected: false,
+		},
+		{
+			name:     "plain error",
+			err:      errors.New("unable to run instance"),
+			expected: false,
+		},
+		{
+			name:     "nil",
+			err:      nil,
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := IsInvalidMachineConfiguration(tc.err); actual != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestMachineErrorUnwrap(t *testing.T) {
+	cause := errors.New("RequestLimitExceeded")
+	err := DeleteMachine(cause, "unable to terminate instance %q", "i-0123456789abcdef0")
+
+	if err.Unwrap() != cause {
+		t.Errorf("expected the cause to be returned, got %v", err.Unwrap())
+	}
+	expected := `unable to terminate instance "i-0123456789abcdef0": RequestLimitExceeded`
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err.Error())
+	}
+}
+
+func TestClassifyEC2ErrorCode(t *testing.T) {
+	testCases := []struct {
+		code     string
+		expected common.MachineStatusError
+	}{
+		{code: "InvalidParameterValue", expected: common.InvalidConfigurationMachineError},
+		{code: "InvalidAMIID.NotFound", expected: common.InvalidConfigurationMachineError},
+		{code: "InvalidAMIID.Malformed", expected: common.InvalidConfigurationMachineError},
+		{code: "InvalidSubnetID.NotFound", expected: common.InvalidConfigurationMachineError},
+		{code: "InsufficientCapacityReservation", expected: common.InvalidConfigurationMachineError},
+		{code: "RequestLimitExceeded", expected: common.CreateMachineError},
+		{code: "InsufficientInstanceCapacity", expected: common.CreateMachineError},
+		{code: "InternalError", expected: common.CreateMachineError},
+		{code: "", expected: common.CreateMachineError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.code, func(t *testing.T) {
+			if actual := classifyEC2ErrorCode(tc.code, common.CreateMachineError); actual != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+)
+
+// maxUserDataSize is the largest user data EC2 accepts, in bytes after
+// base64 encoding.
+const maxUserDataSize = 16 * 1024
+
+// isGzipped returns true if data starts with the gzip magic bytes.
+func isGzipped(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// encodeUserData returns data base64 encoded for RunInstances. When compress
+// is set, data is gzipped first unless it is already gzip-compressed.
+func encodeUserData(data []byte, compress bool) (string, error) {
+	if compress && !isGzipped(data) {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return "", err
+		}
+		if err := w.Close(); err != nil {
+			return "", err
+		}
+		data = buf.Bytes()
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	if len(encoded) > maxUserDataSize {
+		return "", InvalidMachineConfiguration("user data is %d bytes after base64 encoding, more than the EC2 limit of %d", len(encoded), maxUserDataSize)
+	}
+	return encoded, nil
+}
@@ -0,0 +1,152 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package machine
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+)
+
+func gzipUserData(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		t.Fatalf("unable to compress user data: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unable to compress user data: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func gunzipUserData(t *testing.T, data []byte) []byte {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("unable to decompress user data: %v", err)
+	}
+	decompressed, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unable to decompress user data: %v", err)
+	}
+	return decompressed
+}
+
+func decodeUserData(t *testing.T, encoded string) []byte {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("unable to decode user data: %v", err)
+	}
+	return data
+}
+
+func TestEncodeUserData(t *testing.T) {
+	ignition := bytes.Repeat([]byte(`{"ignition": {"version": "2.2.0"}}`), 1000)
+	gzipped := gzipUserData(t, ignition)
+
+	testCases := []struct {
+		name     string
+		data     []byte
+		compress bool
+		// expected is the user data after base64 decoding, and gunzipping
+		// when gunzip is set.
+		expected []byte
+		gunzip   bool
+	}{
+		{
+			name:     "uncompressed",
+			data:     []byte("#cloud-config\n"),
+			expected: []byte("#cloud-config\n"),
+		},
+		{
+			name:     "compressed",
+			data:     ignition,
+			compress: true,
+			expected: ignition,
+			gunzip:   true,
+		},
+		{
+			name:     "already gzipped",
+			data:     gzipped,
+			compress: true,
+			expected: gzipped,
+		},
+		{
+			name:     "already gzipped without compression",
+			data:     gzipped,
+			expected: gzipped,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := encodeUserData(tc.data, tc.compress)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			actual := decodeUserData(t, encoded)
+			if tc.gunzip {
+				actual = gunzipUserData(t, actual)
+			}
+			if !bytes.Equal(actual, tc.expected) {
+				t.Errorf("expected user data %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEncodeUserDataTooLarge(t *testing.T) {
+	// Random data doesn't compress, so it stays above the limit even when
+	// it is gzipped.
+	random := make([]byte, maxUserDataSize)
+	rand.New(rand.NewSource(1)).Read(random)
+	compressible := bytes.Repeat([]byte("a"), maxUserDataSize)
+
+	testCases := []struct {
+		name     string
+		data     []byte
+		compress bool
+		err      bool
+	}{
+		{
+			name: "uncompressed",
+			data: compressible,
+			err:  true,
+		},
+		{
+			name:     "compressed below the limit",
+			data:     compressible,
+			compress: true,
+		},
+		{
+			name:     "compressed above the limit",
+			data:     random,
+			compress: true,
+			err:      true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := encodeUserData(tc.data, tc.compress)
+			if tc.err && !IsInvalidMachineConfiguration(err) {
+				t.Errorf("expected an invalid machine configuration error, got %v", err)
+			}
+			if !tc.err && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -20,6 +20,111 @@ import (
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 type AWSMachineProviderConfig struct {
 	metav1.TypeMeta `json:",inline"`
+
+	// MetadataServiceOptions configures how the instance metadata service
+	// (IMDS) may be accessed from the instance.
+	MetadataServiceOptions *MetadataServiceOptions `json:"metadataServiceOptions,omitempty"`
+
+	// RootVolume configures the EBS root volume of the instance. Defaults
+	// to a 120GiB gp3 volume.
+	RootVolume *EBSBlockDeviceSpec `json:"rootVolume,omitempty"`
+
+	// BlockDevices are additional EBS volumes attached at launch. Their
+	// device names must not collide with the root device of the AMI.
+	BlockDevices []BlockDeviceMappingSpec `json:"blockDevices,omitempty"`
+
+	// IAMInstanceProfile is the instance profile attached to the instance,
+	// given either as a name or as a full ARN
+	// (arn:aws:iam::<account>:instance-profile/<path/name>).
+	IAMInstanceProfile string `json:"iamInstanceProfile,omitempty"`
+
+	// CapacityReservationID targets a specific on-demand capacity
+	// reservation. When empty the instance may use any open reservation.
+	CapacityReservationID string `json:"capacityReservationId,omitempty"`
+
+	// Placement configures where the instance is placed.
+	Placement *Placement `json:"placement,omitempty"`
+
+	// NetworkInterfaces explicitly lists the network interfaces to attach
+	// at launch, each with its own subnet and security groups.
+	NetworkInterfaces []NetworkInterfaceSpec `json:"networkInterfaces,omitempty"`
+
+	// SecondaryPrivateIPCount is the number of secondary private IP
+	// addresses assigned to the primary network interface at launch.
+	SecondaryPrivateIPCount *int64 `json:"secondaryPrivateIpCount,omitempty"`
+
+	// DisableAPITermination enables EC2 termination protection on the
+	// instance. It has to be cleared before the instance can be terminated.
+	DisableAPITermination *bool `json:"disableApiTermination,omitempty"`
+
+	// ServiceEndpoints overrides the endpoint URL of individual AWS
+	// services, e.g. to reach EC2 through a private endpoint.
+	ServiceEndpoints []ServiceEndpoint `json:"serviceEndpoints,omitempty"`
+
+	// TargetGroupARNs are the ELBv2 target groups the instance is
+	// registered with, e.g. the network load balancer fronting the API
+	// server for control plane machines.
+	TargetGroupARNs []string `json:"targetGroupArns,omitempty"`
+
+	// EnclaveOptions enables AWS Nitro Enclaves on the instance. Only some
+	// instance types support enclaves.
+	EnclaveOptions *bool `json:"enclaveOptions,omitempty"`
+
+	// HibernationEnabled configures the instance for hibernation.
+	// Hibernation requires an encrypted root volume.
+	HibernationEnabled *bool `json:"hibernationEnabled,omitempty"`
+
+	// CPUOptions sets the number of CPU cores and threads per core of the
+	// instance. When nil the instance type defaults are used.
+	CPUOptions *CPUOptions `json:"cpuOptions,omitempty"`
+
+	// MaintenanceOptions configures how EC2 maintains the instance.
+	MaintenanceOptions *MaintenanceOptions `json:"maintenanceOptions,omitempty"`
+
+	// PrivateDNSName configures the hostname type of the instance and the
+	// DNS records created for it.
+	PrivateDNSName *PrivateDNSNameOptions `json:"privateDnsName,omitempty"`
+
+	// LaunchTemplate launches the instance from an existing launch
+	// template. Fields set in this provider config take precedence over
+	// the values in the template. No defaults are applied to a provider
+	// config that references a template.
+	LaunchTemplate *LaunchTemplateReference `json:"launchTemplate,omitempty"`
+
+	// EBSOptimized requests EBS optimization for the instance. When nil
+	// the instance type default applies.
+	EBSOptimized *bool `json:"ebsOptimized,omitempty"`
+
+	// AssociateElasticIP gives the instance a stable public address by
+	// associating an Elastic IP once it is running. A new address is
+	// allocated, and released with the machine, unless
+	// ElasticIPAllocationID is set.
+	AssociateElasticIP bool `json:"associateElasticIp,omitempty"`
+
+	// ElasticIPAllocationID is an existing Elastic IP to associate with the
+	// instance instead of allocating a new one. It requires
+	// AssociateElasticIP and is left allocated when the machine is deleted.
+	ElasticIPAllocationID string `json:"elasticIpAllocationId,omitempty"`
+
+	// PrivateIP pins the private IPv4 address of the primary network
+	// interface. It must fall within the CIDR of the instance's subnet.
+	PrivateIP *string `json:"privateIp,omitempty"`
+
+	// InstanceInitiatedShutdownBehavior is what happens to the instance
+	// when it is shut down from within the guest OS: stop or terminate.
+	// Defaults to terminate.
+	InstanceInitiatedShutdownBehavior ShutdownBehavior `json:"instanceInitiatedShutdownBehavior,omitempty"`
+
+	// NameTagTemplate is a Go text/template used to render the Name tag of
+	// the instance. It can reference the machine's .Name and .Namespace,
+	// the .ClusterID and the instance's .AvailabilityZone. When empty the
+	// machine name is used.
+	NameTagTemplate string `json:"nameTagTemplate,omitempty"`
+
+	// CompressUserData gzips the user data before it is base64 encoded, to
+	// keep large payloads such as ignition configs under the 16KB EC2
+	// limit. User data that is already gzip-compressed is left unchanged.
+	CompressUserData bool `json:"compressUserData,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -36,3 +141,228 @@ type AWSMachineProviderStatus struct {
 type AWSClusterProviderStatus struct {
 	metav1.TypeMeta `json:",inline"`
 }
+
+// MetadataServiceHTTPTokens describes whether instance metadata requests
+// must carry a session token.
+type MetadataServiceHTTPTokens string
+
+const (
+	// MetadataServiceHTTPTokensOptional allows both IMDSv1 and IMDSv2 requests.
+	MetadataServiceHTTPTokensOptional MetadataServiceHTTPTokens = "optional"
+	// MetadataServiceHTTPTokensRequired only allows IMDSv2 requests.
+	MetadataServiceHTTPTokensRequired MetadataServiceHTTPTokens = "required"
+)
+
+// MetadataServiceOptions mirrors the EC2 instance metadata options.
+type MetadataServiceOptions struct {
+	// HTTPTokens is either optional or required. Setting it to required
+	// enforces IMDSv2. Defaults to optional.
+	HTTPTokens MetadataServiceHTTPTokens `json:"httpTokens,omitempty"`
+
+	// HTTPPutResponseHopLimit is the hop limit for PUT responses from the
+	// metadata service. Zero leaves the EC2 default in place.
+	HTTPPutResponseHopLimit int64 `json:"httpPutResponseHopLimit,omitempty"`
+}
+
+// VolumeType is an EBS volume type.
+type VolumeType string
+
+// Supported EBS volume types.
+const (
+	VolumeTypeStandard VolumeType = "standard"
+	VolumeTypeGP2      VolumeType = "gp2"
+	VolumeTypeGP3      VolumeType = "gp3"
+	VolumeTypeIO1      VolumeType = "io1"
+	VolumeTypeIO2      VolumeType = "io2"
+	VolumeTypeST1      VolumeType = "st1"
+	VolumeTypeSC1      VolumeType = "sc1"
+)
+
+// EBSBlockDeviceSpec describes an EBS volume attached at launch.
+type EBSBlockDeviceSpec struct {
+	// VolumeSize is the size of the volume in GiB.
+	VolumeSize int64 `json:"volumeSize,omitempty"`
+
+	// VolumeType is the EBS volume type, e.g. gp2 or gp3.
+	VolumeType VolumeType `json:"volumeType,omitempty"`
+
+	// IOPS is the number of provisioned I/O operations per second. It is
+	// required for io1 and io2 volumes and optional for gp3.
+	IOPS int64 `json:"iops,omitempty"`
+
+	// Throughput is the provisioned throughput in MiB/s. It is only valid
+	// for gp3 volumes.
+	Throughput int64 `json:"throughput,omitempty"`
+
+	// Encrypted indicates whether the volume should be encrypted.
+	Encrypted *bool `json:"encrypted,omitempty"`
+
+	// KMSKey is the customer managed KMS key used to encrypt the volume. It
+	// may be a key ID, a key ARN or an alias ("alias/name"). When empty the
+	// account's default EBS key is used.
+	KMSKey string `json:"kmsKey,omitempty"`
+
+	// DeleteOnTermination indicates whether the volume is deleted when the
+	// instance is terminated. Defaults to true for the root volume. It can
+	// only be chosen at launch.
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+}
+
+// BlockDeviceMappingSpec describes an additional volume and the device
+// name it is exposed under.
+type BlockDeviceMappingSpec struct {
+	// DeviceName is the device name exposed to the instance, e.g. /dev/sdf.
+	DeviceName string `json:"deviceName"`
+
+	// EBS describes the volume backing the device.
+	EBS EBSBlockDeviceSpec `json:"ebs"`
+}
+
+// Placement describes the placement of an instance.
+type Placement struct {
+	// GroupName is the name of an existing placement group (cluster,
+	// spread or partition) to launch the instance into.
+	GroupName string `json:"groupName,omitempty"`
+
+	// PartitionNumber is the partition to launch into when GroupName
+	// refers to a partition placement group. Partitions start at 1.
+	PartitionNumber int64 `json:"partitionNumber,omitempty"`
+
+	// Tenancy is the tenancy of the instance: default, dedicated or host.
+	Tenancy Tenancy `json:"tenancy,omitempty"`
+
+	// HostID is the dedicated host to launch onto. It requires Tenancy to
+	// be host and can't be combined with HostResourceGroupARN.
+	HostID string `json:"hostId,omitempty"`
+
+	// HostResourceGroupARN is the host resource group to auto-place the
+	// instance into. It requires Tenancy to be host and can't be combined
+	// with HostID.
+	HostResourceGroupARN string `json:"hostResourceGroupArn,omitempty"`
+}
+
+// Tenancy is the tenancy of an instance.
+type Tenancy string
+
+// Supported instance tenancies.
+const (
+	TenancyDefault   Tenancy = "default"
+	TenancyDedicated Tenancy = "dedicated"
+	TenancyHost      Tenancy = "host"
+)
+
+// NetworkInterfaceSpec describes a network interface attached at launch.
+type NetworkInterfaceSpec struct {
+	// DeviceIndex is the position of the interface on the instance. The
+	// primary interface has index 0.
+	DeviceIndex int64 `json:"deviceIndex"`
+
+	// SubnetID is the subnet the interface is created in.
+	SubnetID string `json:"subnetId"`
+
+	// SecurityGroupIDs are the security groups associated with the
+	// interface.
+	SecurityGroupIDs []string `json:"securityGroupIds,omitempty"`
+
+	// DeleteOnTermination indicates whether the interface is deleted when
+	// the instance is terminated.
+	DeleteOnTermination *bool `json:"deleteOnTermination,omitempty"`
+
+	// InterfaceType is the type of the interface: interface (the default)
+	// or efa for an Elastic Fabric Adapter.
+	InterfaceType NetworkInterfaceType `json:"interfaceType,omitempty"`
+}
+
+// NetworkInterfaceType is the type of a network interface.
+type NetworkInterfaceType string
+
+// Supported network interface types.
+const (
+	NetworkInterfaceTypeInterface NetworkInterfaceType = "interface"
+	NetworkInterfaceTypeEFA       NetworkInterfaceType = "efa"
+)
+
+// ServiceEndpoint overrides the endpoint of a single AWS service.
+type ServiceEndpoint struct {
+	// Name is the AWS service name, e.g. ec2, elasticloadbalancing or sts.
+	Name string `json:"name"`
+
+	// URL is the fully qualified endpoint, including the scheme.
+	URL string `json:"url"`
+}
+
+// CPUOptions describes the CPU topology of an instance.
+type CPUOptions struct {
+	// CoreCount is the number of CPU cores.
+	CoreCount int64 `json:"coreCount,omitempty"`
+
+	// ThreadsPerCore is the number of threads per core. Set it to 1 to
+	// disable hyperthreading.
+	ThreadsPerCore int64 `json:"threadsPerCore,omitempty"`
+}
+
+// AutoRecovery describes whether EC2 recovers an instance after an
+// underlying hardware failure.
+type AutoRecovery string
+
+// Supported auto-recovery settings.
+const (
+	AutoRecoveryDefault  AutoRecovery = "default"
+	AutoRecoveryDisabled AutoRecovery = "disabled"
+)
+
+// MaintenanceOptions mirrors the EC2 instance maintenance options.
+type MaintenanceOptions struct {
+	// AutoRecovery is either default or disabled. When empty the EC2
+	// default, which recovers supported instances, applies.
+	AutoRecovery AutoRecovery `json:"autoRecovery,omitempty"`
+}
+
+// HostnameType is the type of hostname assigned to an instance.
+type HostnameType string
+
+// Supported hostname types.
+const (
+	HostnameTypeIPName       HostnameType = "ip-name"
+	HostnameTypeResourceName HostnameType = "resource-name"
+)
+
+// PrivateDNSNameOptions mirrors the EC2 private DNS name options.
+type PrivateDNSNameOptions struct {
+	// HostnameType is either ip-name or resource-name. Resource name
+	// hostnames are only available in VPCs that support them.
+	HostnameType HostnameType `json:"hostnameType,omitempty"`
+
+	// EnableResourceNameDNSARecord creates a DNS A record for the resource
+	// name hostname.
+	EnableResourceNameDNSARecord *bool `json:"enableResourceNameDnsARecord,omitempty"`
+
+	// EnableResourceNameDNSAAAARecord creates a DNS AAAA record for the
+	// resource name hostname.
+	EnableResourceNameDNSAAAARecord *bool `json:"enableResourceNameDnsAAAARecord,omitempty"`
+}
+
+// LaunchTemplateReference identifies an EC2 launch template by ID or by
+// name.
+type LaunchTemplateReference struct {
+	// ID is the launch template ID.
+	ID string `json:"id,omitempty"`
+
+	// Name is the launch template name. Exactly one of ID and Name must be
+	// set.
+	Name string `json:"name,omitempty"`
+
+	// Version is the template version to use. When empty the default
+	// version of the template is used.
+	Version string `json:"version,omitempty"`
+}
+
+// ShutdownBehavior is the behavior of an instance shut down from the
+// guest OS.
+type ShutdownBehavior string
+
+// Supported instance-initiated shutdown behaviors.
+const (
+	ShutdownBehaviorStop      ShutdownBehavior = "stop"
+	ShutdownBehaviorTerminate ShutdownBehavior = "terminate"
+)
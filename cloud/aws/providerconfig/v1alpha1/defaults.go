@@ -0,0 +1,64 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// DefaultRootVolumeSize is the root volume size in GiB used when none is set.
+	DefaultRootVolumeSize = 120
+	// DefaultRootVolumeType is the root volume type used when none is set.
+	DefaultRootVolumeType = VolumeTypeGP3
+)
+
+func addDefaultingFuncs(scheme *runtime.Scheme) error {
+	scheme.AddTypeDefaultingFunc(&AWSMachineProviderConfig{}, func(obj interface{}) {
+		SetDefaults_AWSMachineProviderConfig(obj.(*AWSMachineProviderConfig))
+	})
+	return nil
+}
+
+// SetDefaults_AWSMachineProviderConfig fills in the fields a user may leave
+// empty. AWSProviderConfigCodec.DecodeFromProviderConfig runs it after every
+// decode. Nothing is defaulted when a launch template is set, so the values
+// in the template are not overridden.
+func SetDefaults_AWSMachineProviderConfig(obj *AWSMachineProviderConfig) {
+	if obj.LaunchTemplate != nil {
+		return
+	}
+	if obj.MetadataServiceOptions == nil {
+		obj.MetadataServiceOptions = &MetadataServiceOptions{}
+	}
+	if obj.MetadataServiceOptions.HTTPTokens == "" {
+		obj.MetadataServiceOptions.HTTPTokens = MetadataServiceHTTPTokensOptional
+	}
+	if obj.InstanceInitiatedShutdownBehavior == "" {
+		obj.InstanceInitiatedShutdownBehavior = ShutdownBehaviorTerminate
+	}
+	if obj.RootVolume == nil {
+		obj.RootVolume = &EBSBlockDeviceSpec{}
+	}
+	if obj.RootVolume.VolumeSize == 0 {
+		obj.RootVolume.VolumeSize = DefaultRootVolumeSize
+	}
+	if obj.RootVolume.VolumeType == "" {
+		obj.RootVolume.VolumeType = DefaultRootVolumeType
+	}
+	if obj.RootVolume.DeleteOnTermination == nil {
+		deleteOnTermination := true
+		obj.RootVolume.DeleteOnTermination = &deleteOnTermination
+	}
+}
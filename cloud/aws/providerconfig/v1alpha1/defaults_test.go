@@ -0,0 +1,153 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+)
+
+func decodeMachineProviderConfig(t *testing.T, raw string) *AWSMachineProviderConfig {
+	codec, err := NewCodec()
+	if err != nil {
+		t.Fatalf("unable to create codec: %v", err)
+	}
+	providerConfig := clusterv1.ProviderConfig{
+		Value: &runtime.RawExtension{Raw: []byte(raw)},
+	}
+	config := &AWSMachineProviderConfig{}
+	if err := codec.DecodeFromProviderConfig(providerConfig, config); err != nil {
+		t.Fatalf("unable to decode provider config: %v", err)
+	}
+	return config
+}
+
+// roundTripMachineProviderConfig encodes config and decodes the result.
+func roundTripMachineProviderConfig(t *testing.T, config *AWSMachineProviderConfig) *AWSMachineProviderConfig {
+	codec, err := NewCodec()
+	if err != nil {
+		t.Fatalf("unable to create codec: %v", err)
+	}
+	providerConfig, err := codec.EncodeToProviderConfig(config)
+	if err != nil {
+		t.Fatalf("unable to encode provider config: %v", err)
+	}
+	return decodeMachineProviderConfig(t, string(providerConfig.Value.Raw))
+}
+
+func TestDecodeDefaultsRootVolume(t *testing.T) {
+	config := decodeMachineProviderConfig(t, `{}`)
+
+	if config.RootVolume == nil {
+		t.Fatalf("expected root volume to be defaulted")
+	}
+	if config.RootVolume.VolumeSize != DefaultRootVolumeSize {
+		t.Errorf("expected root volume size %d, got %d", DefaultRootVolumeSize, config.RootVolume.VolumeSize)
+	}
+	if config.RootVolume.VolumeType != VolumeTypeGP3 {
+		t.Errorf("expected root volume type %q, got %q", VolumeTypeGP3, config.RootVolume.VolumeType)
+	}
+}
+
+func TestDecodeKeepsRootVolume(t *testing.T) {
+	config := decodeMachineProviderConfig(t, `{"rootVolume": {"volumeSize": 50, "volumeType": "io1", "iops": 3000}}`)
+
+	if config.RootVolume.VolumeSize != 50 {
+		t.Errorf("expected root volume size 50, got %d", config.RootVolume.VolumeSize)
+	}
+	if config.RootVolume.VolumeType != VolumeTypeIO1 {
+		t.Errorf("expected root volume type %q, got %q", VolumeTypeIO1, config.RootVolume.VolumeType)
+	}
+}
+
+func TestDecodeSkipsDefaultsWithLaunchTemplate(t *testing.T) {
+	config := decodeMachineProviderConfig(t, `{"launchTemplate": {"name": "workers"}}`)
+
+	if config.RootVolume != nil {
+		t.Errorf("expected root volume to be left to the launch template, got %+v", config.RootVolume)
+	}
+	if config.MetadataServiceOptions != nil {
+		t.Errorf("expected metadata service options to be left to the launch template, got %+v", config.MetadataServiceOptions)
+	}
+	if config.InstanceInitiatedShutdownBehavior != "" {
+		t.Errorf("expected shutdown behavior to be left to the launch template, got %q", config.InstanceInitiatedShutdownBehavior)
+	}
+}
+
+func TestDecodeRootVolumeDeleteOnTermination(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		expected bool
+	}{
+		{
+			name:     "defaulted",
+			raw:      `{"rootVolume": {}}`,
+			expected: true,
+		},
+		{
+			name:     "explicitly true",
+			raw:      `{"rootVolume": {"deleteOnTermination": true}}`,
+			expected: true,
+		},
+		{
+			name:     "explicitly false",
+			raw:      `{"rootVolume": {"deleteOnTermination": false}}`,
+			expected: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := roundTripMachineProviderConfig(t, decodeMachineProviderConfig(t, tc.raw))
+
+			deleteOnTermination := config.RootVolume.DeleteOnTermination
+			if deleteOnTermination == nil {
+				t.Fatalf("expected deleteOnTermination to be set")
+			}
+			if *deleteOnTermination != tc.expected {
+				t.Errorf("expected deleteOnTermination %v, got %v", tc.expected, *deleteOnTermination)
+			}
+		})
+	}
+}
+
+func TestDecodeInstanceInitiatedShutdownBehavior(t *testing.T) {
+	testCases := []struct {
+		name     string
+		raw      string
+		expected ShutdownBehavior
+	}{
+		{
+			name:     "defaulted",
+			raw:      `{}`,
+			expected: ShutdownBehaviorTerminate,
+		},
+		{
+			name:     "stop",
+			raw:      `{"instanceInitiatedShutdownBehavior": "stop"}`,
+			expected: ShutdownBehaviorStop,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			config := roundTripMachineProviderConfig(t, decodeMachineProviderConfig(t, tc.raw))
+
+			if config.InstanceInitiatedShutdownBehavior != tc.expected {
+				t.Errorf("expected shutdown behavior %q, got %q", tc.expected, config.InstanceInitiatedShutdownBehavior)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"bytes"
+	"text/template"
+)
+
+// NameTagData is the data a NameTagTemplate is rendered with.
+// +k8s:deepcopy-gen=false
+type NameTagData struct {
+	// Name is the name of the machine.
+	Name string
+	// Namespace is the namespace of the machine.
+	Namespace string
+	// ClusterID identifies the cluster the machine belongs to.
+	ClusterID string
+	// AvailabilityZone is the availability zone the instance runs in.
+	AvailabilityZone string
+}
+
+// RenderNameTag renders the Name tag template nameTagTemplate with data.
+// Referencing a field NameTagData doesn't have is an error.
+func RenderNameTag(nameTagTemplate string, data NameTagData) (string, error) {
+	tmpl, err := template.New("nameTag").Option("missingkey=error").Parse(nameTagTemplate)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
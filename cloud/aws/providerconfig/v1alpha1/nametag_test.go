@@ -0,0 +1,71 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"testing"
+)
+
+func TestRenderNameTag(t *testing.T) {
+	data := NameTagData{
+		Name:             "worker-0",
+		Namespace:        "default",
+		ClusterID:        "prod-7f3a",
+		AvailabilityZone: "us-east-1a",
+	}
+	testCases := []struct {
+		name     string
+		template string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "cluster ID and availability zone",
+			template: "{{ .ClusterID }}-{{ .AvailabilityZone }}-{{ .Name }}",
+			expected: "prod-7f3a-us-east-1a-worker-0",
+		},
+		{
+			name:     "namespace",
+			template: "{{ .Namespace }}/{{ .Name }}",
+			expected: "default/worker-0",
+		},
+		{
+			name:     "unknown field",
+			template: "{{ .Bogus }}",
+			err:      true,
+		},
+		{
+			name:     "doesn't parse",
+			template: "{{ .ClusterID }-{{ .Name }}",
+			err:      true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, err := RenderNameTag(tc.template, data)
+			if tc.err {
+				if err == nil {
+					t.Errorf("expected an error, got %q", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
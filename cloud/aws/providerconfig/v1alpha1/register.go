@@ -29,6 +29,7 @@ import (
 type AWSProviderConfigCodec struct {
 	encoder runtime.Encoder
 	decoder runtime.Decoder
+	scheme  *runtime.Scheme
 }
 
 const GroupName = "awsproviderconfig"
@@ -42,7 +43,7 @@ var (
 )
 
 func init() {
-	localSchemeBuilder.Register(addKnownTypes)
+	localSchemeBuilder.Register(addKnownTypes, addDefaultingFuncs)
 }
 
 func addKnownTypes(scheme *runtime.Scheme) error {
@@ -85,6 +86,7 @@ func NewCodec() (*AWSProviderConfigCodec, error) {
 	codec := AWSProviderConfigCodec{
 		encoder: encoder,
 		decoder: codecFactory.UniversalDecoder(SchemeGroupVersion),
+		scheme:  scheme,
 	}
 	return &codec, nil
 }
@@ -96,6 +98,14 @@ func (codec *AWSProviderConfigCodec) DecodeFromProviderConfig(providerConfig clu
 			return fmt.Errorf("decoding failure: %v", err)
 		}
 	}
+	// The versioning decoder skips defaulting when it decodes straight into
+	// out, so apply the defaults here.
+	codec.scheme.Default(out)
+	if config, ok := out.(*AWSMachineProviderConfig); ok {
+		if errs := ValidateAWSMachineProviderConfig(config); len(errs) > 0 {
+			return fmt.Errorf("invalid provider config: %v", errs.ToAggregate())
+		}
+	}
 	return nil
 }
 
@@ -0,0 +1,95 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	clusterv1 "sigs.k8s.io/cluster-api/pkg/apis/cluster/v1alpha1"
+)
+
+func TestEncodeOmitsUnsetOptions(t *testing.T) {
+	codec, err := NewCodec()
+	if err != nil {
+		t.Fatalf("unable to create codec: %v", err)
+	}
+	providerConfig, err := codec.EncodeToProviderConfig(&AWSMachineProviderConfig{})
+	if err != nil {
+		t.Fatalf("unable to encode provider config: %v", err)
+	}
+	for _, field := range []string{"metadataServiceOptions", "placement", "maintenanceOptions"} {
+		if strings.Contains(string(providerConfig.Value.Raw), field) {
+			t.Errorf("expected %s to be omitted, got %s", field, providerConfig.Value.Raw)
+		}
+	}
+}
+
+func TestRoundTripGP3RootVolume(t *testing.T) {
+	encrypted := true
+	rootVolume := EBSBlockDeviceSpec{
+		VolumeSize: 200,
+		VolumeType: VolumeTypeGP3,
+		IOPS:       6000,
+		Throughput: 500,
+		Encrypted:  &encrypted,
+	}
+	config := roundTripMachineProviderConfig(t, &AWSMachineProviderConfig{RootVolume: rootVolume.DeepCopy()})
+
+	// DeleteOnTermination is defaulted on decode.
+	config.RootVolume.DeleteOnTermination = nil
+	if !reflect.DeepEqual(*config.RootVolume, rootVolume) {
+		t.Errorf("expected root volume %+v, got %+v", rootVolume, *config.RootVolume)
+	}
+}
+
+func TestRoundTripBlockDevices(t *testing.T) {
+	encrypted := true
+	keep := false
+	blockDevices := []BlockDeviceMappingSpec{
+		{
+			DeviceName: "/dev/sdf",
+			EBS:        EBSBlockDeviceSpec{VolumeSize: 500, VolumeType: VolumeTypeGP3, Encrypted: &encrypted},
+		},
+		{
+			DeviceName: "/dev/sdg",
+			EBS:        EBSBlockDeviceSpec{VolumeSize: 1000, VolumeType: VolumeTypeIO2, IOPS: 16000, DeleteOnTermination: &keep},
+		},
+		{
+			DeviceName: "/dev/sdh",
+			EBS:        EBSBlockDeviceSpec{VolumeSize: 2000, VolumeType: VolumeTypeST1},
+		},
+	}
+	config := &AWSMachineProviderConfig{BlockDevices: blockDevices}
+	config = roundTripMachineProviderConfig(t, config.DeepCopy())
+
+	if !reflect.DeepEqual(config.BlockDevices, blockDevices) {
+		t.Errorf("expected block devices %+v, got %+v", blockDevices, config.BlockDevices)
+	}
+}
+
+func TestDecodeRejectsInvalidProviderConfig(t *testing.T) {
+	codec, err := NewCodec()
+	if err != nil {
+		t.Fatalf("unable to create codec: %v", err)
+	}
+	providerConfig := clusterv1.ProviderConfig{
+		Value: &runtime.RawExtension{Raw: []byte(`{"nameTagTemplate": "{{ .Bogus }}"}`)},
+	}
+	if err := codec.DecodeFromProviderConfig(providerConfig, &AWSMachineProviderConfig{}); err == nil {
+		t.Errorf("expected decoding an invalid provider config to fail")
+	}
+}
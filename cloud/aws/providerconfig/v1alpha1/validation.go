@@ -0,0 +1,230 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// supportedVolumeTypes are the EBS volume types a volume may request.
+var supportedVolumeTypes = []string{
+	string(VolumeTypeStandard),
+	string(VolumeTypeGP2),
+	string(VolumeTypeGP3),
+	string(VolumeTypeIO1),
+	string(VolumeTypeIO2),
+	string(VolumeTypeST1),
+	string(VolumeTypeSC1),
+}
+
+var (
+	// iamInstanceProfileNameRegexp matches an IAM instance profile name.
+	iamInstanceProfileNameRegexp = regexp.MustCompile(`^[\w+=,.@-]{1,128}$`)
+	// iamInstanceProfileARNRegexp matches an IAM instance profile ARN,
+	// including an optional path before the name.
+	iamInstanceProfileARNRegexp = regexp.MustCompile(`^arn:aws(-[a-z]+)*:iam::\d{12}:instance-profile/([\w+=,.@-]+/)*[\w+=,.@-]{1,128}$`)
+)
+
+// ValidateAWSMachineProviderConfig checks the rules of a machine provider
+// config that can be verified from the spec alone, without calling AWS.
+// AWSProviderConfigCodec.DecodeFromProviderConfig runs it after defaulting.
+func ValidateAWSMachineProviderConfig(config *AWSMachineProviderConfig) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if config.MetadataServiceOptions != nil {
+		allErrs = append(allErrs, validateMetadataServiceOptions(config.MetadataServiceOptions, field.NewPath("metadataServiceOptions"))...)
+	}
+	if config.RootVolume != nil {
+		allErrs = append(allErrs, validateEBSBlockDevice(config.RootVolume, field.NewPath("rootVolume"))...)
+	}
+	deviceNames := map[string]bool{}
+	for i := range config.BlockDevices {
+		fldPath := field.NewPath("blockDevices").Index(i)
+		deviceName := config.BlockDevices[i].DeviceName
+		switch {
+		case deviceName == "":
+			allErrs = append(allErrs, field.Required(fldPath.Child("deviceName"), "device name must be set"))
+		case deviceNames[deviceName]:
+			allErrs = append(allErrs, field.Duplicate(fldPath.Child("deviceName"), deviceName))
+		}
+		deviceNames[deviceName] = true
+		allErrs = append(allErrs, validateEBSBlockDevice(&config.BlockDevices[i].EBS, fldPath.Child("ebs"))...)
+	}
+	if profile := config.IAMInstanceProfile; profile != "" && !iamInstanceProfileNameRegexp.MatchString(profile) && !iamInstanceProfileARNRegexp.MatchString(profile) {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("iamInstanceProfile"), profile, "must be an instance profile name or ARN"))
+	}
+	if config.Placement != nil {
+		allErrs = append(allErrs, validatePlacement(config.Placement, field.NewPath("placement"))...)
+	}
+	for i, networkInterface := range config.NetworkInterfaces {
+		allErrs = append(allErrs, validateNetworkInterface(networkInterface, field.NewPath("networkInterfaces").Index(i))...)
+	}
+	if config.LaunchTemplate != nil {
+		allErrs = append(allErrs, validateLaunchTemplate(config.LaunchTemplate, field.NewPath("launchTemplate"))...)
+	}
+	if config.CPUOptions != nil {
+		allErrs = append(allErrs, validateCPUOptions(config.CPUOptions, field.NewPath("cpuOptions"))...)
+	}
+	if config.MaintenanceOptions != nil {
+		switch config.MaintenanceOptions.AutoRecovery {
+		case "", AutoRecoveryDefault, AutoRecoveryDisabled:
+		default:
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("maintenanceOptions", "autoRecovery"), config.MaintenanceOptions.AutoRecovery, []string{string(AutoRecoveryDefault), string(AutoRecoveryDisabled)}))
+		}
+	}
+	if config.PrivateDNSName != nil {
+		switch config.PrivateDNSName.HostnameType {
+		case "", HostnameTypeIPName, HostnameTypeResourceName:
+		default:
+			allErrs = append(allErrs, field.NotSupported(field.NewPath("privateDnsName", "hostnameType"), config.PrivateDNSName.HostnameType, []string{string(HostnameTypeIPName), string(HostnameTypeResourceName)}))
+		}
+	}
+	for i, endpoint := range config.ServiceEndpoints {
+		allErrs = append(allErrs, validateServiceEndpoint(endpoint, field.NewPath("serviceEndpoints").Index(i))...)
+	}
+	if config.NameTagTemplate != "" {
+		if _, err := RenderNameTag(config.NameTagTemplate, NameTagData{}); err != nil {
+			allErrs = append(allErrs, field.Invalid(field.NewPath("nameTagTemplate"), config.NameTagTemplate, fmt.Sprintf("unable to render template: %v", err)))
+		}
+	}
+	if config.ElasticIPAllocationID != "" && !config.AssociateElasticIP {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("associateElasticIp"), config.AssociateElasticIP, "associateElasticIp must be true when elasticIpAllocationId is set"))
+	}
+	if config.SecondaryPrivateIPCount != nil && *config.SecondaryPrivateIPCount < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("secondaryPrivateIpCount"), *config.SecondaryPrivateIPCount, "must not be negative"))
+	}
+	if config.PrivateIP != nil && net.ParseIP(*config.PrivateIP).To4() == nil {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("privateIp"), *config.PrivateIP, "must be an IPv4 address"))
+	}
+	switch config.InstanceInitiatedShutdownBehavior {
+	case "", ShutdownBehaviorStop, ShutdownBehaviorTerminate:
+	default:
+		allErrs = append(allErrs, field.NotSupported(field.NewPath("instanceInitiatedShutdownBehavior"), config.InstanceInitiatedShutdownBehavior, []string{string(ShutdownBehaviorStop), string(ShutdownBehaviorTerminate)}))
+	}
+	if config.HibernationEnabled != nil && *config.HibernationEnabled {
+		// A launch template may supply the root volume, which can't be
+		// checked from here.
+		templateRootVolume := config.RootVolume == nil && config.LaunchTemplate != nil
+		if !templateRootVolume && (config.RootVolume == nil || config.RootVolume.Encrypted == nil || !*config.RootVolume.Encrypted) {
+			allErrs = append(allErrs, field.Required(field.NewPath("rootVolume", "encrypted"), "the root volume must be encrypted when hibernation is enabled"))
+		}
+	}
+	return allErrs
+}
+
+func validateMetadataServiceOptions(options *MetadataServiceOptions, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch options.HTTPTokens {
+	case "", MetadataServiceHTTPTokensOptional, MetadataServiceHTTPTokensRequired:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("httpTokens"), options.HTTPTokens, []string{string(MetadataServiceHTTPTokensOptional), string(MetadataServiceHTTPTokensRequired)}))
+	}
+	if options.HTTPPutResponseHopLimit < 0 || options.HTTPPutResponseHopLimit > 64 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("httpPutResponseHopLimit"), options.HTTPPutResponseHopLimit, "must be between 1 and 64"))
+	}
+	return allErrs
+}
+
+func validateCPUOptions(options *CPUOptions, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if options.CoreCount < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("coreCount"), options.CoreCount, "must not be negative"))
+	}
+	if options.ThreadsPerCore != 0 && options.ThreadsPerCore != 1 && options.ThreadsPerCore != 2 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("threadsPerCore"), options.ThreadsPerCore, "must be 1 or 2"))
+	}
+	return allErrs
+}
+
+func validatePlacement(placement *Placement, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch placement.Tenancy {
+	case "", TenancyDefault, TenancyDedicated, TenancyHost:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("tenancy"), placement.Tenancy, []string{string(TenancyDefault), string(TenancyDedicated), string(TenancyHost)}))
+	}
+	if placement.PartitionNumber < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("partitionNumber"), placement.PartitionNumber, "partitions start at 1"))
+	}
+	if placement.PartitionNumber != 0 && placement.GroupName == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("groupName"), "groupName must be set when partitionNumber is set"))
+	}
+	if placement.HostID != "" && placement.Tenancy != TenancyHost {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("tenancy"), placement.Tenancy, "tenancy must be host when hostId is set"))
+	}
+	if placement.HostResourceGroupARN != "" {
+		if placement.HostID != "" {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("hostResourceGroupArn"), "hostResourceGroupArn and hostId are mutually exclusive"))
+		}
+		if placement.Tenancy != TenancyHost {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("tenancy"), placement.Tenancy, "tenancy must be host when hostResourceGroupArn is set"))
+		}
+	}
+	return allErrs
+}
+
+func validateNetworkInterface(networkInterface NetworkInterfaceSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch networkInterface.InterfaceType {
+	case "", NetworkInterfaceTypeInterface, NetworkInterfaceTypeEFA:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("interfaceType"), networkInterface.InterfaceType, []string{string(NetworkInterfaceTypeInterface), string(NetworkInterfaceTypeEFA)}))
+	}
+	return allErrs
+}
+
+func validateLaunchTemplate(launchTemplate *LaunchTemplateReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch {
+	case launchTemplate.ID == "" && launchTemplate.Name == "":
+		allErrs = append(allErrs, field.Required(fldPath, "one of id or name must be set"))
+	case launchTemplate.ID != "" && launchTemplate.Name != "":
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("name"), "id and name are mutually exclusive"))
+	}
+	return allErrs
+}
+
+func validateServiceEndpoint(endpoint ServiceEndpoint, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if endpoint.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "service name must be set"))
+	}
+	if u, err := url.Parse(endpoint.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), endpoint.URL, "must be an absolute URL including the scheme"))
+	}
+	return allErrs
+}
+
+func validateEBSBlockDevice(spec *EBSBlockDeviceSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch spec.VolumeType {
+	case "", VolumeTypeStandard, VolumeTypeGP2, VolumeTypeGP3, VolumeTypeIO1, VolumeTypeIO2, VolumeTypeST1, VolumeTypeSC1:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("volumeType"), spec.VolumeType, supportedVolumeTypes))
+	}
+	if spec.Throughput != 0 && spec.VolumeType != VolumeTypeGP3 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("throughput"), spec.Throughput, "throughput can only be set for gp3 volumes"))
+	}
+	if encrypted := spec.Encrypted != nil && *spec.Encrypted; spec.KMSKey != "" && !encrypted {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("encrypted"), encrypted, "encrypted must be true when kmsKey is set"))
+	}
+	if (spec.VolumeType == VolumeTypeIO1 || spec.VolumeType == VolumeTypeIO2) && spec.IOPS == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("iops"), "iops must be set for io1 and io2 volumes"))
+	}
+	return allErrs
+}
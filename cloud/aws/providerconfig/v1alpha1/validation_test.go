@@ -0,0 +1,408 @@
+// Copyright © 2018 The Kubernetes Authors.
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateAWSMachineProviderConfig(t *testing.T) {
+	enabled := true
+	disabled := false
+	privateIP := "10.0.1.25"
+	malformedPrivateIP := "10.0.1"
+	ipv6PrivateIP := "fd00::25"
+	secondaryPrivateIPCount := int64(4)
+	negativeSecondaryPrivateIPCount := int64(-1)
+	testCases := []struct {
+		name   string
+		config AWSMachineProviderConfig
+		// fields lists the paths of the expected errors, in order.
+		fields []string
+	}{
+		{
+			name:   "empty",
+			config: AWSMachineProviderConfig{},
+		},
+		{
+			name: "gp3 with iops and throughput",
+			config: AWSMachineProviderConfig{
+				RootVolume: &EBSBlockDeviceSpec{VolumeType: VolumeTypeGP3, IOPS: 4000, Throughput: 250},
+			},
+		},
+		{
+			name: "gp2 with throughput",
+			config: AWSMachineProviderConfig{
+				RootVolume: &EBSBlockDeviceSpec{VolumeType: VolumeTypeGP2, Throughput: 250},
+			},
+			fields: []string{"rootVolume.throughput"},
+		},
+		{
+			name: "io1 with iops",
+			config: AWSMachineProviderConfig{
+				RootVolume: &EBSBlockDeviceSpec{VolumeType: VolumeTypeIO1, IOPS: 3000},
+			},
+		},
+		{
+			name: "io2 without iops",
+			config: AWSMachineProviderConfig{
+				BlockDevices: []BlockDeviceMappingSpec{
+					{DeviceName: "/dev/sdf", EBS: EBSBlockDeviceSpec{VolumeType: VolumeTypeGP3}},
+					{DeviceName: "/dev/sdg", EBS: EBSBlockDeviceSpec{VolumeType: VolumeTypeIO2}},
+				},
+			},
+			fields: []string{"blockDevices[1].ebs.iops"},
+		},
+		{
+			name:   "instance profile name",
+			config: AWSMachineProviderConfig{IAMInstanceProfile: "nodes.example.com"},
+		},
+		{
+			name:   "instance profile ARN",
+			config: AWSMachineProviderConfig{IAMInstanceProfile: "arn:aws:iam::123456789012:instance-profile/cluster/nodes"},
+		},
+		{
+			name:   "instance profile GovCloud ARN",
+			config: AWSMachineProviderConfig{IAMInstanceProfile: "arn:aws-us-gov:iam::123456789012:instance-profile/nodes"},
+		},
+		{
+			name:   "instance profile role ARN",
+			config: AWSMachineProviderConfig{IAMInstanceProfile: "arn:aws:iam::123456789012:role/nodes"},
+			fields: []string{"iamInstanceProfile"},
+		},
+		{
+			name:   "instance profile name with spaces",
+			config: AWSMachineProviderConfig{IAMInstanceProfile: "my nodes"},
+			fields: []string{"iamInstanceProfile"},
+		},
+		{
+			name:   "partition in a placement group",
+			config: AWSMachineProviderConfig{Placement: &Placement{GroupName: "hpc", PartitionNumber: 2}},
+		},
+		{
+			name:   "partition without a placement group",
+			config: AWSMachineProviderConfig{Placement: &Placement{PartitionNumber: 2}},
+			fields: []string{"placement.groupName"},
+		},
+		{
+			name:   "dedicated host",
+			config: AWSMachineProviderConfig{Placement: &Placement{Tenancy: TenancyHost, HostID: "h-0123456789abcdef0"}},
+		},
+		{
+			name:   "dedicated host without host tenancy",
+			config: AWSMachineProviderConfig{Placement: &Placement{Tenancy: TenancyDedicated, HostID: "h-0123456789abcdef0"}},
+			fields: []string{"placement.tenancy"},
+		},
+		{
+			name: "host resource group",
+			config: AWSMachineProviderConfig{Placement: &Placement{
+				Tenancy:              TenancyHost,
+				HostResourceGroupARN: "arn:aws:resource-groups:us-east-1:123456789012:group/hosts",
+			}},
+		},
+		{
+			name: "host resource group and dedicated host",
+			config: AWSMachineProviderConfig{Placement: &Placement{
+				Tenancy:              TenancyHost,
+				HostID:               "h-0123456789abcdef0",
+				HostResourceGroupARN: "arn:aws:resource-groups:us-east-1:123456789012:group/hosts",
+			}},
+			fields: []string{"placement.hostResourceGroupArn"},
+		},
+		{
+			name: "host resource group without host tenancy",
+			config: AWSMachineProviderConfig{Placement: &Placement{
+				HostResourceGroupARN: "arn:aws:resource-groups:us-east-1:123456789012:group/hosts",
+			}},
+			fields: []string{"placement.tenancy"},
+		},
+		{
+			name: "hibernation with an encrypted root volume",
+			config: AWSMachineProviderConfig{
+				HibernationEnabled: &enabled,
+				RootVolume:         &EBSBlockDeviceSpec{Encrypted: &enabled},
+			},
+		},
+		{
+			name: "hibernation with an unencrypted root volume",
+			config: AWSMachineProviderConfig{
+				HibernationEnabled: &enabled,
+				RootVolume:         &EBSBlockDeviceSpec{Encrypted: &disabled},
+			},
+			fields: []string{"rootVolume.encrypted"},
+		},
+		{
+			name: "hibernation without encryption set",
+			config: AWSMachineProviderConfig{
+				HibernationEnabled: &enabled,
+				RootVolume:         &EBSBlockDeviceSpec{},
+			},
+			fields: []string{"rootVolume.encrypted"},
+		},
+		{
+			name: "hibernation with the root volume from a launch template",
+			config: AWSMachineProviderConfig{
+				HibernationEnabled: &enabled,
+				LaunchTemplate:     &LaunchTemplateReference{Name: "workers"},
+			},
+		},
+		{
+			name: "hibernation disabled with an unencrypted root volume",
+			config: AWSMachineProviderConfig{
+				HibernationEnabled: &disabled,
+				RootVolume:         &EBSBlockDeviceSpec{Encrypted: &disabled},
+			},
+		},
+		{
+			name: "service endpoint",
+			config: AWSMachineProviderConfig{ServiceEndpoints: []ServiceEndpoint{
+				{Name: "ec2", URL: "https://vpce-0123456789abcdef0.ec2.us-east-1.vpce.amazonaws.com"},
+			}},
+		},
+		{
+			name: "service endpoint without a scheme",
+			config: AWSMachineProviderConfig{ServiceEndpoints: []ServiceEndpoint{
+				{Name: "ec2", URL: "https://ec2.internal"},
+				{Name: "sts", URL: "sts.internal"},
+			}},
+			fields: []string{"serviceEndpoints[1].url"},
+		},
+		{
+			name: "service endpoint that doesn't parse",
+			config: AWSMachineProviderConfig{ServiceEndpoints: []ServiceEndpoint{
+				{Name: "ec2", URL: "https://ec2.internal:port"},
+			}},
+			fields: []string{"serviceEndpoints[0].url"},
+		},
+		{
+			name: "service endpoint without a name",
+			config: AWSMachineProviderConfig{ServiceEndpoints: []ServiceEndpoint{
+				{URL: "https://ec2.internal"},
+			}},
+			fields: []string{"serviceEndpoints[0].name"},
+		},
+		{
+			name:   "name tag template",
+			config: AWSMachineProviderConfig{NameTagTemplate: "{{ .ClusterID }}-{{ .AvailabilityZone }}-{{ .Name }}"},
+		},
+		{
+			name:   "name tag template that doesn't parse",
+			config: AWSMachineProviderConfig{NameTagTemplate: "{{ .ClusterID }-{{ .Name }}"},
+			fields: []string{"nameTagTemplate"},
+		},
+		{
+			name:   "name tag template with an unknown field",
+			config: AWSMachineProviderConfig{NameTagTemplate: "{{ .Bogus }}"},
+			fields: []string{"nameTagTemplate"},
+		},
+		{
+			name:   "new elastic IP",
+			config: AWSMachineProviderConfig{AssociateElasticIP: true},
+		},
+		{
+			name: "existing elastic IP",
+			config: AWSMachineProviderConfig{
+				AssociateElasticIP:    true,
+				ElasticIPAllocationID: "eipalloc-0123456789abcdef0",
+			},
+		},
+		{
+			name:   "existing elastic IP without association",
+			config: AWSMachineProviderConfig{ElasticIPAllocationID: "eipalloc-0123456789abcdef0"},
+			fields: []string{"associateElasticIp"},
+		},
+		{
+			name:   "launch template by name",
+			config: AWSMachineProviderConfig{LaunchTemplate: &LaunchTemplateReference{Name: "workers", Version: "3"}},
+		},
+		{
+			name:   "launch template by ID",
+			config: AWSMachineProviderConfig{LaunchTemplate: &LaunchTemplateReference{ID: "lt-0123456789abcdef0"}},
+		},
+		{
+			name:   "empty launch template reference",
+			config: AWSMachineProviderConfig{LaunchTemplate: &LaunchTemplateReference{}},
+			fields: []string{"launchTemplate"},
+		},
+		{
+			name:   "launch template by ID and name",
+			config: AWSMachineProviderConfig{LaunchTemplate: &LaunchTemplateReference{ID: "lt-0123456789abcdef0", Name: "workers"}},
+			fields: []string{"launchTemplate.name"},
+		},
+		{
+			name:   "private IP",
+			config: AWSMachineProviderConfig{PrivateIP: &privateIP},
+		},
+		{
+			name:   "malformed private IP",
+			config: AWSMachineProviderConfig{PrivateIP: &malformedPrivateIP},
+			fields: []string{"privateIp"},
+		},
+		{
+			name:   "IPv6 private IP",
+			config: AWSMachineProviderConfig{PrivateIP: &ipv6PrivateIP},
+			fields: []string{"privateIp"},
+		},
+		{
+			name:   "IMDSv2 required",
+			config: AWSMachineProviderConfig{MetadataServiceOptions: &MetadataServiceOptions{HTTPTokens: MetadataServiceHTTPTokensRequired, HTTPPutResponseHopLimit: 2}},
+		},
+		{
+			name:   "unknown HTTP tokens setting",
+			config: AWSMachineProviderConfig{MetadataServiceOptions: &MetadataServiceOptions{HTTPTokens: "bogus"}},
+			fields: []string{"metadataServiceOptions.httpTokens"},
+		},
+		{
+			name:   "hop limit above 64",
+			config: AWSMachineProviderConfig{MetadataServiceOptions: &MetadataServiceOptions{HTTPPutResponseHopLimit: 500}},
+			fields: []string{"metadataServiceOptions.httpPutResponseHopLimit"},
+		},
+		{
+			name:   "negative hop limit",
+			config: AWSMachineProviderConfig{MetadataServiceOptions: &MetadataServiceOptions{HTTPPutResponseHopLimit: -1}},
+			fields: []string{"metadataServiceOptions.httpPutResponseHopLimit"},
+		},
+		{
+			name:   "dedicated tenancy",
+			config: AWSMachineProviderConfig{Placement: &Placement{Tenancy: TenancyDedicated}},
+		},
+		{
+			name:   "unknown tenancy",
+			config: AWSMachineProviderConfig{Placement: &Placement{Tenancy: "shared"}},
+			fields: []string{"placement.tenancy"},
+		},
+		{
+			name:   "negative partition",
+			config: AWSMachineProviderConfig{Placement: &Placement{GroupName: "hpc", PartitionNumber: -3}},
+			fields: []string{"placement.partitionNumber"},
+		},
+		{
+			name:   "stop on shutdown",
+			config: AWSMachineProviderConfig{InstanceInitiatedShutdownBehavior: ShutdownBehaviorStop},
+		},
+		{
+			name:   "unknown shutdown behavior",
+			config: AWSMachineProviderConfig{InstanceInitiatedShutdownBehavior: "hibernate"},
+			fields: []string{"instanceInitiatedShutdownBehavior"},
+		},
+		{
+			name: "EFA network interface",
+			config: AWSMachineProviderConfig{NetworkInterfaces: []NetworkInterfaceSpec{
+				{DeviceIndex: 0, SubnetID: "subnet-0123456789abcdef0", InterfaceType: NetworkInterfaceTypeEFA},
+			}},
+		},
+		{
+			name: "unknown network interface type",
+			config: AWSMachineProviderConfig{NetworkInterfaces: []NetworkInterfaceSpec{
+				{DeviceIndex: 0, SubnetID: "subnet-0123456789abcdef0", InterfaceType: "trunk"},
+			}},
+			fields: []string{"networkInterfaces[0].interfaceType"},
+		},
+		{
+			name:   "auto-recovery disabled",
+			config: AWSMachineProviderConfig{MaintenanceOptions: &MaintenanceOptions{AutoRecovery: AutoRecoveryDisabled}},
+		},
+		{
+			name:   "unknown auto-recovery setting",
+			config: AWSMachineProviderConfig{MaintenanceOptions: &MaintenanceOptions{AutoRecovery: "enabled"}},
+			fields: []string{"maintenanceOptions.autoRecovery"},
+		},
+		{
+			name:   "resource name hostname",
+			config: AWSMachineProviderConfig{PrivateDNSName: &PrivateDNSNameOptions{HostnameType: HostnameTypeResourceName}},
+		},
+		{
+			name:   "unknown hostname type",
+			config: AWSMachineProviderConfig{PrivateDNSName: &PrivateDNSNameOptions{HostnameType: "fqdn"}},
+			fields: []string{"privateDnsName.hostnameType"},
+		},
+		{
+			name:   "unknown volume type",
+			config: AWSMachineProviderConfig{RootVolume: &EBSBlockDeviceSpec{VolumeType: "gp9"}},
+			fields: []string{"rootVolume.volumeType"},
+		},
+		{
+			name: "duplicate device names",
+			config: AWSMachineProviderConfig{
+				BlockDevices: []BlockDeviceMappingSpec{
+					{DeviceName: "/dev/sdf", EBS: EBSBlockDeviceSpec{VolumeType: VolumeTypeGP3}},
+					{DeviceName: "/dev/sdg", EBS: EBSBlockDeviceSpec{VolumeType: VolumeTypeGP3}},
+					{DeviceName: "/dev/sdf", EBS: EBSBlockDeviceSpec{VolumeType: VolumeTypeGP3}},
+				},
+			},
+			fields: []string{"blockDevices[2].deviceName"},
+		},
+		{
+			name: "empty device name",
+			config: AWSMachineProviderConfig{
+				BlockDevices: []BlockDeviceMappingSpec{
+					{EBS: EBSBlockDeviceSpec{VolumeType: VolumeTypeGP3}},
+				},
+			},
+			fields: []string{"blockDevices[0].deviceName"},
+		},
+		{
+			name:   "customer managed KMS key",
+			config: AWSMachineProviderConfig{RootVolume: &EBSBlockDeviceSpec{Encrypted: &enabled, KMSKey: "alias/nodes"}},
+		},
+		{
+			name:   "KMS key on an unencrypted volume",
+			config: AWSMachineProviderConfig{RootVolume: &EBSBlockDeviceSpec{Encrypted: &disabled, KMSKey: "alias/nodes"}},
+			fields: []string{"rootVolume.encrypted"},
+		},
+		{
+			name:   "KMS key without encryption set",
+			config: AWSMachineProviderConfig{RootVolume: &EBSBlockDeviceSpec{KMSKey: "alias/nodes"}},
+			fields: []string{"rootVolume.encrypted"},
+		},
+		{
+			name:   "hyperthreading disabled",
+			config: AWSMachineProviderConfig{CPUOptions: &CPUOptions{CoreCount: 4, ThreadsPerCore: 1}},
+		},
+		{
+			name:   "too many threads per core",
+			config: AWSMachineProviderConfig{CPUOptions: &CPUOptions{CoreCount: 4, ThreadsPerCore: 7}},
+			fields: []string{"cpuOptions.threadsPerCore"},
+		},
+		{
+			name:   "negative core count",
+			config: AWSMachineProviderConfig{CPUOptions: &CPUOptions{CoreCount: -2}},
+			fields: []string{"cpuOptions.coreCount"},
+		},
+		{
+			name:   "secondary private IPs",
+			config: AWSMachineProviderConfig{SecondaryPrivateIPCount: &secondaryPrivateIPCount},
+		},
+		{
+			name:   "negative secondary private IP count",
+			config: AWSMachineProviderConfig{SecondaryPrivateIPCount: &negativeSecondaryPrivateIPCount},
+			fields: []string{"secondaryPrivateIpCount"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fields := []string{}
+			for _, err := range ValidateAWSMachineProviderConfig(&tc.config) {
+				fields = append(fields, err.Field)
+			}
+			if tc.fields == nil {
+				tc.fields = []string{}
+			}
+			if !reflect.DeepEqual(fields, tc.fields) {
+				t.Errorf("expected errors for %v, got %v", tc.fields, fields)
+			}
+		})
+	}
+}